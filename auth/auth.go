@@ -0,0 +1,355 @@
+// Package auth builds authenticated Cloud Identity service clients from a
+// ServiceConfig, supporting a service account key file (with optional
+// domain-wide delegation), Application Default Credentials, and IAM service
+// account impersonation.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+
+	"yhuang/learning/config"
+)
+
+// RequiredScopes are the scopes needed to manage Cloud Identity groups and
+// Admin Directory group memberships.
+var RequiredScopes = []string{
+	"https://www.googleapis.com/auth/cloud-identity.groups",
+	"https://www.googleapis.com/auth/admin.directory.group",
+	"https://www.googleapis.com/auth/admin.directory.group.member",
+}
+
+// Mode selects how ServiceConfig obtains credentials.
+type Mode int
+
+const (
+	// ModeKeyFile authenticates with a service account JSON key file,
+	// optionally delegated via domain-wide delegation.
+	ModeKeyFile Mode = iota
+	// ModeADC authenticates with Application Default Credentials, so the
+	// tool can run on GCE/GKE/Cloud Run or against a local `gcloud`
+	// session without shipping a key.
+	ModeADC
+	// ModeImpersonate authenticates by impersonating a target service
+	// account's short-lived credentials via IAM Credentials.
+	ModeImpersonate
+)
+
+// ServiceConfig describes how to authenticate to the Cloud Identity and
+// Admin Directory APIs.
+type ServiceConfig struct {
+	Mode Mode
+
+	// ModeKeyFile. CredentialsJSON takes precedence over
+	// ServiceAccountKeyPath when both are set.
+	ServiceAccountKeyPath string
+	CredentialsJSON       []byte
+	DelegatedUser         string
+
+	// ModeImpersonate
+	TargetServiceAccount string
+	Delegates            []string
+	Subject              string
+
+	CustomerID string
+}
+
+// NewServiceConfig builds a ModeKeyFile config from a service account key
+// file on disk, optionally delegated to delegatedUser via domain-wide
+// delegation.
+func NewServiceConfig(keyPath, delegatedUser, customerID string) (*ServiceConfig, error) {
+	absPath, err := filepath.Abs(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key path: %v", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("service account key file does not exist: %s", absPath)
+	}
+
+	return &ServiceConfig{
+		Mode:                  ModeKeyFile,
+		ServiceAccountKeyPath: absPath,
+		DelegatedUser:         delegatedUser,
+		CustomerID:            customerID,
+	}, nil
+}
+
+// NewServiceConfigFromCredentials builds a ModeKeyFile config from either an
+// inline service account JSON blob or a path to one (resolved via
+// config.ReadCredentials), so credentials can come from a secret manager
+// mount as well as a plain file.
+func NewServiceConfigFromCredentials(credentialsOrPath, delegatedUser, customerID string) (*ServiceConfig, error) {
+	data, err := config.ReadCredentials(credentialsOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials: %v", err)
+	}
+
+	return &ServiceConfig{
+		Mode:            ModeKeyFile,
+		CredentialsJSON: data,
+		DelegatedUser:   delegatedUser,
+		CustomerID:      customerID,
+	}, nil
+}
+
+// NewADCServiceConfig builds a config that authenticates with Application
+// Default Credentials (GCE/GKE/Cloud Run metadata server, or a local
+// `gcloud auth application-default login` session).
+func NewADCServiceConfig(customerID string) *ServiceConfig {
+	return &ServiceConfig{
+		Mode:       ModeADC,
+		CustomerID: customerID,
+	}
+}
+
+// NewImpersonationServiceConfig builds a config that authenticates by
+// impersonating targetServiceAccount, optionally through a delegation chain,
+// and optionally asserting subject for domain-wide delegation on the
+// impersonated service account.
+func NewImpersonationServiceConfig(targetServiceAccount string, delegates []string, subject, customerID string) *ServiceConfig {
+	return &ServiceConfig{
+		Mode:                 ModeImpersonate,
+		TargetServiceAccount: targetServiceAccount,
+		Delegates:            delegates,
+		Subject:              subject,
+		CustomerID:           customerID,
+	}
+}
+
+// CreateServiceWithoutDelegation builds a Cloud Identity service from a
+// service account key file, without domain-wide delegation.
+func CreateServiceWithoutDelegation(ctx context.Context, config *ServiceConfig) (*cloudidentity.Service, error) {
+	credOpt := option.WithCredentialsFile(config.ServiceAccountKeyPath)
+	if config.CredentialsJSON != nil {
+		credOpt = option.WithCredentialsJSON(config.CredentialsJSON)
+	}
+
+	service, err := cloudidentity.NewService(ctx,
+		credOpt,
+		option.WithScopes("https://www.googleapis.com/auth/cloud-identity.groups"))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Identity service: %v", err)
+	}
+	return service, nil
+}
+
+// CreateServiceWithDelegation builds a Cloud Identity service from a service
+// account key file, impersonating config.DelegatedUser via domain-wide
+// delegation.
+func CreateServiceWithDelegation(ctx context.Context, config *ServiceConfig) (*cloudidentity.Service, error) {
+	if config.DelegatedUser == "" {
+		return nil, fmt.Errorf("delegated user is required for delegation")
+	}
+
+	data := config.CredentialsJSON
+	if data == nil {
+		var err error
+		data, err = os.ReadFile(config.ServiceAccountKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials: %v", err)
+		}
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON(data, RequiredScopes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %v", err)
+	}
+
+	jwtConfig.Subject = config.DelegatedUser
+	client := jwtConfig.Client(ctx)
+
+	service, err := cloudidentity.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud identity service: %v", err)
+	}
+
+	return service, nil
+}
+
+// CreateServiceWithADC builds a Cloud Identity service from Application
+// Default Credentials, so the caller doesn't need to ship a service account
+// key at all. This resolves credentials from GOOGLE_APPLICATION_CREDENTIALS,
+// a local `gcloud auth application-default login` session, or the
+// GCE/GKE/Cloud Run metadata server, in that order.
+func CreateServiceWithADC(ctx context.Context, scopes ...string) (*cloudidentity.Service, error) {
+	if len(scopes) == 0 {
+		scopes = RequiredScopes
+	}
+
+	creds, err := google.FindDefaultCredentials(ctx, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %v", err)
+	}
+
+	service, err := cloudidentity.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud identity service: %v", err)
+	}
+	return service, nil
+}
+
+// CreateServiceWithImpersonation builds a Cloud Identity service backed by
+// short-lived credentials for targetServiceAccount, obtained via the IAM
+// Credentials API rather than a long-lived JSON key. delegates, if set, is
+// a chain of intermediate service accounts the caller's own credentials must
+// be authorized to impersonate before reaching targetServiceAccount. subject
+// enables domain-wide delegation on the impersonated service account, as
+// with CreateServiceWithDelegation.
+func CreateServiceWithImpersonation(ctx context.Context, targetServiceAccount string, delegates []string, subject string, scopes ...string) (*cloudidentity.Service, error) {
+	if len(scopes) == 0 {
+		scopes = RequiredScopes
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: targetServiceAccount,
+		Scopes:          scopes,
+		Delegates:       delegates,
+		Subject:         subject,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonated token source: %v", err)
+	}
+
+	service, err := cloudidentity.NewService(ctx, option.WithTokenSource(ts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud identity service: %v", err)
+	}
+	return service, nil
+}
+
+// CreateService dispatches to the constructor matching config.Mode, so
+// callers can pick an authentication mode without hardcoding which
+// constructor to call.
+func CreateService(ctx context.Context, config *ServiceConfig) (*cloudidentity.Service, error) {
+	switch config.Mode {
+	case ModeADC:
+		return CreateServiceWithADC(ctx, RequiredScopes...)
+	case ModeImpersonate:
+		return CreateServiceWithImpersonation(ctx, config.TargetServiceAccount, config.Delegates, config.Subject, RequiredScopes...)
+	case ModeKeyFile:
+		if config.DelegatedUser != "" {
+			return CreateServiceWithDelegation(ctx, config)
+		}
+		return CreateServiceWithoutDelegation(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown service config mode: %v", config.Mode)
+	}
+}
+
+// clientOptions resolves config into the option.ClientOption set needed to
+// construct any Google API client (Cloud Identity, Admin Directory, ...)
+// under the given mode, so new services don't need their own copy of the
+// mode switch.
+func clientOptions(ctx context.Context, config *ServiceConfig) ([]option.ClientOption, error) {
+	switch config.Mode {
+	case ModeADC:
+		creds, err := google.FindDefaultCredentials(ctx, RequiredScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default credentials: %v", err)
+		}
+		return []option.ClientOption{option.WithCredentials(creds)}, nil
+
+	case ModeImpersonate:
+		ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: config.TargetServiceAccount,
+			Scopes:          RequiredScopes,
+			Delegates:       config.Delegates,
+			Subject:         config.Subject,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create impersonated token source: %v", err)
+		}
+		return []option.ClientOption{option.WithTokenSource(ts)}, nil
+
+	case ModeKeyFile:
+		if config.DelegatedUser == "" {
+			if config.CredentialsJSON != nil {
+				return []option.ClientOption{option.WithCredentialsJSON(config.CredentialsJSON)}, nil
+			}
+			return []option.ClientOption{option.WithCredentialsFile(config.ServiceAccountKeyPath)}, nil
+		}
+
+		data := config.CredentialsJSON
+		if data == nil {
+			var err error
+			data, err = os.ReadFile(config.ServiceAccountKeyPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read credentials: %v", err)
+			}
+		}
+		jwtConfig, err := google.JWTConfigFromJSON(data, RequiredScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse service account key: %v", err)
+		}
+		jwtConfig.Subject = config.DelegatedUser
+		return []option.ClientOption{option.WithHTTPClient(jwtConfig.Client(ctx))}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown service config mode: %v", config.Mode)
+	}
+}
+
+// CreateDirectoryService builds an Admin Directory service for config,
+// using the same authentication mode as CreateService. Admin Directory is
+// needed alongside Cloud Identity for operations with no Cloud Identity
+// equivalent, such as ListUserGroups.
+func CreateDirectoryService(ctx context.Context, config *ServiceConfig) (*admin.Service, error) {
+	opts, err := clientOptions(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := admin.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Admin Directory service: %v", err)
+	}
+	return service, nil
+}
+
+// VerifyTokenAccess reads config's service account key, requests a token for
+// it (applying domain-wide delegation if DelegatedUser is set), and returns
+// both the resulting credentials and the raw token. Callers should treat the
+// returned token as a secret and avoid logging or printing it unless the
+// caller has opted in (see cmd/gidentity's `token verify --debug-tokens`,
+// the one place this module prints it).
+func VerifyTokenAccess(ctx context.Context, config *ServiceConfig) (*google.Credentials, *oauth2.Token, error) {
+	data := config.CredentialsJSON
+	if data == nil {
+		var err error
+		data, err = os.ReadFile(config.ServiceAccountKeyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read credentials: %v", err)
+		}
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON(data, RequiredScopes...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse service account key: %v", err)
+	}
+
+	if config.DelegatedUser != "" {
+		jwtConfig.Subject = config.DelegatedUser
+	}
+
+	token, err := jwtConfig.TokenSource(ctx).Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get token: %v", err)
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, data, RequiredScopes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return creds, token, nil
+}
@@ -0,0 +1,221 @@
+// Package authz turns group membership in Cloud Identity / Admin Directory
+// into an authorization decision, in the spirit of the dex Google connector
+// (hosted domain + group whitelist checks), but as a standalone Go API any
+// authn/authz middleware can call directly.
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/cloudidentity/v1"
+)
+
+// Decision is the result of evaluating a user against an Authorizer's
+// configured rules.
+type Decision struct {
+	Allowed bool
+	// Reason is a short human-readable explanation, e.g. "matched allowed
+	// group" or "hosted domain not permitted".
+	Reason string
+	// MatchedRule is the specific hosted domain or group email that
+	// produced the decision, if any.
+	MatchedRule string
+}
+
+// Config controls how an Authorizer evaluates users.
+type Config struct {
+	// HostedDomains, if non-empty, restricts access to users whose email
+	// domain is in the list. Evaluated before group membership.
+	HostedDomains []string
+	// AllowedGroups is the set of group emails a user must belong to
+	// (directly or transitively, per Transitive) to be allowed. If empty,
+	// all users passing the hosted domain and denied-group checks are
+	// allowed.
+	AllowedGroups []string
+	// DeniedGroups is the set of group emails that always reject a user,
+	// evaluated before AllowedGroups so an explicit deny wins.
+	DeniedGroups []string
+	// Transitive resolves nested group membership via
+	// ListTransitiveMemberships instead of only direct membership via
+	// ListUserGroups.
+	Transitive bool
+	// CustomerID is passed to ListTransitiveMemberships when Transitive is
+	// true.
+	CustomerID string
+	// CacheTTL controls how long a user's resolved group memberships are
+	// cached before the next Authorize call re-queries the Directory API.
+	// Zero disables caching.
+	CacheTTL time.Duration
+}
+
+// membershipResolver is the subset of *groups.Client that Authorize needs to
+// resolve a user's group memberships. It exists so the allow/deny decision
+// logic can be unit tested against a fake without standing up fake Cloud
+// Identity and Admin Directory HTTP servers.
+type membershipResolver interface {
+	ListUserGroups(ctx context.Context, email string) ([]*admin.Group, error)
+	ListTransitiveMemberships(ctx context.Context, customerID, memberKey string) ([]*cloudidentity.GroupRelation, error)
+}
+
+// Authorizer evaluates users against a Config on top of a membershipResolver
+// (normally a *groups.Client).
+type Authorizer struct {
+	client membershipResolver
+	config Config
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+
+	stopSweep chan struct{}
+}
+
+type cacheEntry struct {
+	groups    map[string]bool
+	expiresAt time.Time
+}
+
+// NewAuthorizer builds an Authorizer backed by client. If config.CacheTTL is
+// set, a background goroutine periodically sweeps expired cache entries so
+// memory use stays bounded to recently-seen users rather than growing for
+// the life of the process; call Close to stop it.
+func NewAuthorizer(client membershipResolver, config Config) *Authorizer {
+	a := &Authorizer{
+		client:    client,
+		config:    config,
+		cache:     make(map[string]cacheEntry),
+		stopSweep: make(chan struct{}),
+	}
+	if config.CacheTTL > 0 {
+		go a.sweepLoop()
+	}
+	return a
+}
+
+// Close stops the Authorizer's background cache-sweep goroutine, if any. It
+// is safe to call even when CacheTTL is zero.
+func (a *Authorizer) Close() {
+	select {
+	case <-a.stopSweep:
+	default:
+		close(a.stopSweep)
+	}
+}
+
+// sweepLoop periodically evicts expired cache entries so the cache doesn't
+// grow for every user ever seen over the life of the process.
+func (a *Authorizer) sweepLoop() {
+	interval := a.config.CacheTTL
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			a.mu.Lock()
+			for email, entry := range a.cache {
+				if now.After(entry.expiresAt) {
+					delete(a.cache, email)
+				}
+			}
+			a.mu.Unlock()
+		case <-a.stopSweep:
+			return
+		}
+	}
+}
+
+// Authorize resolves email's group memberships and evaluates them against
+// the Authorizer's Config, returning a structured Decision.
+func (a *Authorizer) Authorize(ctx context.Context, email string) (Decision, error) {
+	if len(a.config.HostedDomains) > 0 {
+		domain := domainOf(email)
+		if !contains(a.config.HostedDomains, domain) {
+			return Decision{Allowed: false, Reason: "hosted domain not permitted", MatchedRule: domain}, nil
+		}
+	}
+
+	memberGroups, err := a.groupsFor(ctx, email)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to resolve groups for %s: %v", email, err)
+	}
+
+	for _, denied := range a.config.DeniedGroups {
+		if memberGroups[denied] {
+			return Decision{Allowed: false, Reason: "matched denied group", MatchedRule: denied}, nil
+		}
+	}
+
+	if len(a.config.AllowedGroups) == 0 {
+		return Decision{Allowed: true, Reason: "no allowed-group restriction configured"}, nil
+	}
+
+	for _, allowed := range a.config.AllowedGroups {
+		if memberGroups[allowed] {
+			return Decision{Allowed: true, Reason: "matched allowed group", MatchedRule: allowed}, nil
+		}
+	}
+
+	return Decision{Allowed: false, Reason: "no allowed group matched"}, nil
+}
+
+// groupsFor returns the set of group emails email belongs to, serving from
+// the TTL cache when possible.
+func (a *Authorizer) groupsFor(ctx context.Context, email string) (map[string]bool, error) {
+	if a.config.CacheTTL > 0 {
+		a.mu.Lock()
+		entry, ok := a.cache[email]
+		a.mu.Unlock()
+		if ok && time.Now().Before(entry.expiresAt) {
+			return entry.groups, nil
+		}
+	}
+
+	memberGroups := make(map[string]bool)
+	if a.config.Transitive {
+		relations, err := a.client.ListTransitiveMemberships(ctx, a.config.CustomerID, email)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range relations {
+			memberGroups[rel.GroupKey.Id] = true
+		}
+	} else {
+		directGroups, err := a.client.ListUserGroups(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range directGroups {
+			memberGroups[g.Email] = true
+		}
+	}
+
+	if a.config.CacheTTL > 0 {
+		a.mu.Lock()
+		a.cache[email] = cacheEntry{groups: memberGroups, expiresAt: time.Now().Add(a.config.CacheTTL)}
+		a.mu.Unlock()
+	}
+
+	return memberGroups, nil
+}
+
+func domainOf(email string) string {
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[i+1:]
+	}
+	return ""
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
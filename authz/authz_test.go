@@ -0,0 +1,140 @@
+package authz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/cloudidentity/v1"
+)
+
+// fakeResolver is a membershipResolver test double that returns canned group
+// memberships without any network access.
+type fakeResolver struct {
+	direct     []string
+	transitive []string
+}
+
+func (f *fakeResolver) ListUserGroups(ctx context.Context, email string) ([]*admin.Group, error) {
+	var out []*admin.Group
+	for _, g := range f.direct {
+		out = append(out, &admin.Group{Email: g})
+	}
+	return out, nil
+}
+
+func (f *fakeResolver) ListTransitiveMemberships(ctx context.Context, customerID, memberKey string) ([]*cloudidentity.GroupRelation, error) {
+	var out []*cloudidentity.GroupRelation
+	for _, g := range f.transitive {
+		out = append(out, &cloudidentity.GroupRelation{GroupKey: &cloudidentity.EntityKey{Id: g}})
+	}
+	return out, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		email   string
+		direct  []string
+		allowed bool
+		reason  string
+	}{
+		{
+			name:    "no restrictions allows everyone",
+			config:  Config{},
+			email:   "user@example.com",
+			allowed: true,
+			reason:  "no allowed-group restriction configured",
+		},
+		{
+			name:    "hosted domain rejects mismatched domain",
+			config:  Config{HostedDomains: []string{"example.com"}},
+			email:   "user@other.com",
+			allowed: false,
+			reason:  "hosted domain not permitted",
+		},
+		{
+			name:    "matched allowed group",
+			config:  Config{AllowedGroups: []string{"team@example.com"}},
+			email:   "user@example.com",
+			direct:  []string{"team@example.com"},
+			allowed: true,
+			reason:  "matched allowed group",
+		},
+		{
+			name:    "no allowed group matched",
+			config:  Config{AllowedGroups: []string{"team@example.com"}},
+			email:   "user@example.com",
+			direct:  []string{"other@example.com"},
+			allowed: false,
+			reason:  "no allowed group matched",
+		},
+		{
+			name:    "denied group wins over allowed group",
+			config:  Config{AllowedGroups: []string{"team@example.com"}, DeniedGroups: []string{"team@example.com"}},
+			email:   "user@example.com",
+			direct:  []string{"team@example.com"},
+			allowed: false,
+			reason:  "matched denied group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeResolver{direct: tt.direct}
+			a := NewAuthorizer(resolver, tt.config)
+			defer a.Close()
+
+			decision, err := a.Authorize(context.Background(), tt.email)
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Allowed = %v, want %v", decision.Allowed, tt.allowed)
+			}
+			if decision.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", decision.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAuthorizeTransitive(t *testing.T) {
+	resolver := &fakeResolver{transitive: []string{"nested@example.com"}}
+	a := NewAuthorizer(resolver, Config{
+		Transitive:    true,
+		CustomerID:    "C123",
+		AllowedGroups: []string{"nested@example.com"},
+	})
+	defer a.Close()
+
+	decision, err := a.Authorize(context.Background(), "user@example.com")
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if !decision.Allowed {
+		t.Errorf("Allowed = false, want true via transitive membership")
+	}
+}
+
+func TestGroupsForCachesWithinTTL(t *testing.T) {
+	resolver := &fakeResolver{direct: []string{"team@example.com"}}
+	a := NewAuthorizer(resolver, Config{CacheTTL: time.Hour})
+	defer a.Close()
+
+	if _, err := a.groupsFor(context.Background(), "user@example.com"); err != nil {
+		t.Fatalf("groupsFor() error = %v", err)
+	}
+
+	// Mutate the resolver's backing data; a cached lookup should not see it.
+	resolver.direct = nil
+	groups, err := a.groupsFor(context.Background(), "user@example.com")
+	if err != nil {
+		t.Fatalf("groupsFor() error = %v", err)
+	}
+	if !groups["team@example.com"] {
+		t.Errorf("expected cached membership to still be served within TTL")
+	}
+}
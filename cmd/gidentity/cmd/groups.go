@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"yhuang/learning/groups"
+)
+
+var groupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Manage Cloud Identity groups",
+}
+
+var groupsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List groups for the configured customer",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := groupsClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		all, err := client.ListAllGroups(cmd.Context(), groups.ListGroupsRequest{CustomerID: resolvedConfig.CustomerID})
+		if err != nil {
+			return err
+		}
+
+		return printResult(all, func() error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			defer w.Flush()
+			fmt.Fprintln(w, "NAME\tEMAIL\tDISPLAY NAME")
+			for _, g := range all {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", g.Name, g.GroupKey.Id, g.DisplayName)
+			}
+			return nil
+		})
+	},
+}
+
+var groupsGetCmd = &cobra.Command{
+	Use:   "get NAME",
+	Short: "Get a single group by resource name (e.g. groups/123456789)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := groupsClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		group, err := client.GetGroup(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		return printResult(group, func() error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			defer w.Flush()
+			fmt.Fprintf(w, "Name:\t%s\n", group.Name)
+			fmt.Fprintf(w, "Email:\t%s\n", group.GroupKey.Id)
+			fmt.Fprintf(w, "Display Name:\t%s\n", group.DisplayName)
+			fmt.Fprintf(w, "Description:\t%s\n", group.Description)
+			return nil
+		})
+	},
+}
+
+func init() {
+	groupsCmd.AddCommand(groupsListCmd, groupsGetCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var membersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage Cloud Identity group memberships",
+}
+
+var membersListCmd = &cobra.Command{
+	Use:   "list GROUP",
+	Short: "List members of a group (resource name, e.g. groups/123456789)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := groupsClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		members, err := client.ListMembers(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		return printResult(members, func() error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			defer w.Flush()
+			fmt.Fprintln(w, "MEMBER\tROLE")
+			for _, m := range members {
+				role := ""
+				if len(m.Roles) > 0 {
+					role = m.Roles[0].Name
+				}
+				fmt.Fprintf(w, "%s\t%s\n", m.PreferredMemberKey.Id, role)
+			}
+			return nil
+		})
+	},
+}
+
+var membersAddCmd = &cobra.Command{
+	Use:   "add GROUP EMAIL [ROLE]",
+	Short: "Add a member to a group (role defaults to MEMBER)",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role := "MEMBER"
+		if len(args) == 3 {
+			role = args[2]
+		}
+
+		client, err := groupsClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		membership, err := client.AddMember(cmd.Context(), args[0], args[1], role)
+		if err != nil {
+			return err
+		}
+
+		return printResult(membership, func() error {
+			fmt.Fprintf(cmd.OutOrStdout(), "added %s to %s as %s\n", args[1], args[0], role)
+			return nil
+		})
+	},
+}
+
+var membersRemoveCmd = &cobra.Command{
+	Use:   "remove MEMBERSHIP",
+	Short: "Remove a membership by resource name (e.g. groups/123/memberships/456)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := groupsClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveMember(cmd.Context(), args[0]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	membersCmd.AddCommand(membersListCmd, membersAddCmd, membersRemoveCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// printResult renders v per the resolved --format flag. table is a
+// command-specific renderer for the default, human-friendly table output;
+// json and yaml formats marshal v directly instead.
+func printResult(v any, table func() error) error {
+	switch resolvedConfig.Format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		defer enc.Close()
+		return enc.Encode(v)
+	default:
+		return table()
+	}
+}
@@ -0,0 +1,144 @@
+// Package cmd implements the gidentity CLI's subcommands on top of Cobra.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"yhuang/learning/auth"
+	"yhuang/learning/config"
+	"yhuang/learning/groups"
+)
+
+var (
+	flagConfigFile  string
+	flagKeyFile     string
+	flagADC         bool
+	flagImpersonate string
+	flagSubject     string
+	flagCustomerID  string
+	flagFormat      string
+	flagQPS         float64
+	flagMaxRetries  int
+	flagDebug       bool
+
+	resolvedConfig *config.Config
+	logger         *slog.Logger
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "gidentity",
+	Short: "Manage Cloud Identity groups, memberships, and authorization decisions",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(flagConfigFile, config.Config{
+			KeyFile:     flagKeyFile,
+			ADC:         flagADC,
+			Impersonate: flagImpersonate,
+			Subject:     flagSubject,
+			CustomerID:  flagCustomerID,
+			Format:      flagFormat,
+		})
+		if err != nil {
+			return err
+		}
+		resolvedConfig = cfg
+
+		level := slog.LevelInfo
+		if flagDebug {
+			level = slog.LevelDebug
+		}
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+		return nil
+	},
+}
+
+// Execute runs the gidentity CLI, returning any error from the command that
+// ran.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	defaultConfigPath, _ := config.DefaultPath()
+
+	rootCmd.PersistentFlags().StringVar(&flagConfigFile, "config", defaultConfigPath, "path to config file")
+	rootCmd.PersistentFlags().StringVar(&flagKeyFile, "key-file", "", "service account JSON key file (or inline JSON)")
+	rootCmd.PersistentFlags().BoolVar(&flagADC, "adc", false, "authenticate with Application Default Credentials")
+	rootCmd.PersistentFlags().StringVar(&flagImpersonate, "impersonate", "", "service account to impersonate via IAM Credentials")
+	rootCmd.PersistentFlags().StringVar(&flagSubject, "subject", "", "user to impersonate for domain-wide delegation")
+	rootCmd.PersistentFlags().StringVar(&flagCustomerID, "customer-id", "", "Cloud Identity customer ID")
+	rootCmd.PersistentFlags().StringVar(&flagFormat, "format", "", "output format: json|table|yaml")
+	rootCmd.PersistentFlags().Float64Var(&flagQPS, "qps", 5, "client-side rate limit for Directory API calls")
+	rootCmd.PersistentFlags().IntVar(&flagMaxRetries, "max-retries", 5, "retries for transient Directory API errors")
+	rootCmd.PersistentFlags().BoolVar(&flagDebug, "debug", false, "log request/response details at debug level")
+
+	rootCmd.AddCommand(groupsCmd, membersCmd, userCmd, tokenCmd)
+}
+
+// serviceConfig builds an auth.ServiceConfig from the resolved configuration,
+// picking a mode per the precedence: --impersonate > --adc > --key-file.
+func serviceConfig() (*auth.ServiceConfig, error) {
+	switch {
+	case resolvedConfig.Impersonate != "":
+		return auth.NewImpersonationServiceConfig(resolvedConfig.Impersonate, nil, resolvedConfig.Subject, resolvedConfig.CustomerID), nil
+	case flagADC || resolvedConfig.ADC:
+		return auth.NewADCServiceConfig(resolvedConfig.CustomerID), nil
+	case resolvedConfig.KeyFile != "":
+		return auth.NewServiceConfigFromCredentials(resolvedConfig.KeyFile, resolvedConfig.Subject, resolvedConfig.CustomerID)
+	default:
+		return nil, fmt.Errorf("no credentials configured: pass --key-file, --adc, or --impersonate")
+	}
+}
+
+// groupsClient builds a groups.Client for the resolved ServiceConfig. The
+// Admin Directory service is only needed by `user groups`, so it's built
+// lazily there instead.
+func groupsClient(ctx context.Context) (*groups.Client, error) {
+	sc, err := serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ci, err := auth.CreateService(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups.NewClient(ci, nil, clientOptions()...), nil
+}
+
+// clientOptions builds the groups.ClientOption set shared by groupsClient
+// and directoryClient, from the --qps/--max-retries/--debug flags.
+func clientOptions() []groups.ClientOption {
+	return []groups.ClientOption{
+		groups.WithRateLimit(flagQPS, int(flagQPS)+1),
+		groups.WithMaxRetries(flagMaxRetries),
+		groups.WithLogger(logger),
+	}
+}
+
+// directoryClient mirrors groupsClient but also wires up the Admin Directory
+// service, for subcommands that need `user groups`.
+func directoryClient(ctx context.Context) (*groups.Client, error) {
+	sc, err := serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ci, err := auth.CreateService(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, err := auth.CreateDirectoryService(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups.NewClient(ci, directory, clientOptions()...), nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"yhuang/learning/auth"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Inspect authentication tokens",
+}
+
+var flagDebugTokens bool
+
+var tokenVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify that the configured credentials can obtain a token",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sc, err := serviceConfig()
+		if err != nil {
+			return err
+		}
+		if sc.Mode != auth.ModeKeyFile {
+			return fmt.Errorf("token verify only supports --key-file credentials")
+		}
+
+		creds, token, err := auth.VerifyTokenAccess(cmd.Context(), sc)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "token acquired successfully for project %s\n", creds.ProjectID)
+		if flagDebugTokens {
+			fmt.Fprintf(cmd.OutOrStdout(), "access token: %s\n", token.AccessToken)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tokenVerifyCmd.Flags().BoolVar(&flagDebugTokens, "debug-tokens", false, "print the raw access token (opt-in; tokens are secrets)")
+	tokenCmd.AddCommand(tokenVerifyCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Look up a user's group memberships",
+}
+
+var userGroupsCmd = &cobra.Command{
+	Use:   "groups EMAIL",
+	Short: "List the groups a user directly belongs to",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := directoryClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		userGroups, err := client.ListUserGroups(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		return printResult(userGroups, func() error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			defer w.Flush()
+			fmt.Fprintln(w, "EMAIL\tNAME")
+			for _, g := range userGroups {
+				fmt.Fprintf(w, "%s\t%s\n", g.Email, g.Name)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userGroupsCmd)
+}
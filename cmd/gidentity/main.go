@@ -0,0 +1,17 @@
+// Command gidentity manages Cloud Identity / Admin Directory groups and
+// memberships from the command line.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"yhuang/learning/cmd/gidentity/cmd"
+)
+
+func main() {
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
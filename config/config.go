@@ -0,0 +1,117 @@
+// Package config resolves gidentity's configuration from flags, environment
+// variables, and an optional config file, in that order of precedence, and
+// loads credentials from either a JSON blob or a path to one (mirroring
+// Terraform's `pathorcontents.Read` pattern) so credentials can come from a
+// secret manager mount as well as a plain file on disk.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is gidentity's fully resolved configuration, after applying the
+// flags > env vars > config file precedence.
+type Config struct {
+	KeyFile     string `yaml:"key_file"`
+	ADC         bool   `yaml:"adc"`
+	Impersonate string `yaml:"impersonate"`
+	Subject     string `yaml:"subject"`
+	CustomerID  string `yaml:"customer_id"`
+	Format      string `yaml:"format"`
+}
+
+// DefaultPath is the config file gidentity reads when none is specified
+// explicitly, following the usual `~/.config/<app>/config.yaml` convention.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "gidentity", "config.yaml"), nil
+}
+
+// Load reads the config file at path (if it exists) and layers flags and
+// environment variables on top, in increasing order of precedence:
+// config file < environment variables < flags.
+//
+// flags is the set of values already parsed from the command line; a zero
+// value in flags means "not set" and falls through to the next source.
+func Load(path string, flags Config) (*Config, error) {
+	cfg := &Config{Format: "table"}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+		}
+		if err == nil {
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+			}
+		}
+	}
+
+	if v := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); v != "" {
+		cfg.KeyFile = v
+	}
+	if v := os.Getenv("GIDENTITY_CUSTOMER_ID"); v != "" {
+		cfg.CustomerID = v
+	}
+	if v := os.Getenv("GIDENTITY_SUBJECT"); v != "" {
+		cfg.Subject = v
+	}
+
+	if flags.KeyFile != "" {
+		cfg.KeyFile = flags.KeyFile
+	}
+	if flags.ADC {
+		cfg.ADC = true
+	}
+	if flags.Impersonate != "" {
+		cfg.Impersonate = flags.Impersonate
+	}
+	if flags.Subject != "" {
+		cfg.Subject = flags.Subject
+	}
+	if flags.CustomerID != "" {
+		cfg.CustomerID = flags.CustomerID
+	}
+	if flags.Format != "" {
+		cfg.Format = flags.Format
+	}
+
+	return cfg, nil
+}
+
+// ReadCredentials resolves credentialsOrPath to raw service account JSON: if
+// it looks like a path to an existing file, the file's contents are read; if
+// it looks like inline JSON, it's used as-is. This lets credentials come
+// from a file, a secret manager mount, or an inline blob in an environment
+// variable.
+func ReadCredentials(credentialsOrPath string) ([]byte, error) {
+	trimmed := strings.TrimSpace(credentialsOrPath)
+	if trimmed == "" {
+		return nil, fmt.Errorf("no credentials provided")
+	}
+
+	if json.Valid([]byte(trimmed)) {
+		return []byte(trimmed), nil
+	}
+
+	absPath, err := filepath.Abs(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials path: %v", err)
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file %s: %v", absPath, err)
+	}
+	return data, nil
+}
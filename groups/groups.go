@@ -0,0 +1,270 @@
+// Package groups wraps the Cloud Identity Groups API and the Admin
+// Directory API behind a single, typed client so callers don't have to
+// juggle pagination, parent resource names, and two separate Google API
+// clients themselves.
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"golang.org/x/time/rate"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/cloudidentity/v1"
+)
+
+// Client is a thin, typed wrapper around the Cloud Identity and Admin
+// Directory services used to manage groups and their memberships.
+type Client struct {
+	CI        *cloudidentity.Service
+	Directory *admin.Service
+
+	limiter    *rate.Limiter
+	maxRetries int
+	logger     *slog.Logger
+}
+
+// NewClient builds a Client from already-authenticated Cloud Identity and
+// Admin Directory services. Directory may be nil if the caller only needs
+// the Cloud Identity surface (ListUserGroups is the only method that
+// requires it). Pass WithRateLimit, WithMaxRetries, or WithLogger to
+// configure resilience against the Admin SDK's per-user quotas.
+func NewClient(ci *cloudidentity.Service, directory *admin.Service, opts ...ClientOption) *Client {
+	c := &Client{CI: ci, Directory: directory}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ListGroupsRequest scopes a ListAllGroups call to a customer.
+type ListGroupsRequest struct {
+	CustomerID string
+	PageSize   int64
+}
+
+func (r ListGroupsRequest) parent() string {
+	return fmt.Sprintf("customers/%s", r.CustomerID)
+}
+
+// ListAllGroups auto-paginates Groups.List and returns every group for the
+// given customer.
+func (c *Client) ListAllGroups(ctx context.Context, req ListGroupsRequest) ([]*cloudidentity.Group, error) {
+	var out []*cloudidentity.Group
+
+	call := c.CI.Groups.List().Context(ctx).Parent(req.parent()).View("FULL")
+	if req.PageSize > 0 {
+		call = call.PageSize(req.PageSize)
+	}
+
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := retryDo(ctx, c, "Groups.List", func() (*cloudidentity.ListGroupsResponse, error) { return call.Do() })
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups: %v", err)
+		}
+		out = append(out, resp.Groups...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return out, nil
+}
+
+// GetGroup fetches a single group by its resource name (e.g.
+// "groups/123456789").
+func (c *Client) GetGroup(ctx context.Context, name string) (*cloudidentity.Group, error) {
+	getCall := c.CI.Groups.Get(name).Context(ctx)
+	group, err := retryDo(ctx, c, "Groups.Get", func() (*cloudidentity.Group, error) { return getCall.Do() })
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group %s: %v", name, err)
+	}
+	return group, nil
+}
+
+// CreateGroupRequest describes a group to create under CustomerID.
+type CreateGroupRequest struct {
+	CustomerID  string
+	Email       string
+	DisplayName string
+	Description string
+	// Labels identifies the group type, e.g.
+	// {"cloudidentity.googleapis.com/groups.discussion_forum": ""}.
+	Labels map[string]string
+}
+
+// CreateGroup creates a new Cloud Identity group. The Groups.Create API
+// models this as a long-running Operation, but the generated client exposes
+// no Operations.Get to poll it to completion; in practice Cloud Identity
+// returns group-creation operations already Done. CreateGroup checks that
+// and returns a distinct error rather than silently misdecoding if a future
+// response ever comes back incomplete.
+func (c *Client) CreateGroup(ctx context.Context, req CreateGroupRequest) (*cloudidentity.Group, error) {
+	group := &cloudidentity.Group{
+		Parent:      fmt.Sprintf("customers/%s", req.CustomerID),
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Labels:      req.Labels,
+		GroupKey:    &cloudidentity.EntityKey{Id: req.Email},
+	}
+
+	createCall := c.CI.Groups.Create(group).Context(ctx).InitialGroupConfig("EMPTY")
+	op, err := retryDo(ctx, c, "Groups.Create", func() (*cloudidentity.Operation, error) { return createCall.Do() })
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group %s: %v", req.Email, err)
+	}
+	if !op.Done {
+		return nil, fmt.Errorf("create group %s: operation %s not done yet; this client cannot poll Operations.Get", req.Email, op.Name)
+	}
+
+	var created cloudidentity.Group
+	if err := json.Unmarshal(op.Response, &created); err != nil {
+		return nil, fmt.Errorf("failed to decode created group %s: %v", req.Email, err)
+	}
+	return &created, nil
+}
+
+// DeleteGroup deletes a group by its resource name.
+func (c *Client) DeleteGroup(ctx context.Context, name string) error {
+	deleteCall := c.CI.Groups.Delete(name).Context(ctx)
+	if _, err := retryDo(ctx, c, "Groups.Delete", func() (*cloudidentity.Operation, error) { return deleteCall.Do() }); err != nil {
+		return fmt.Errorf("failed to delete group %s: %v", name, err)
+	}
+	return nil
+}
+
+// ListMembers auto-paginates Memberships.List for the given group resource
+// name.
+func (c *Client) ListMembers(ctx context.Context, groupName string) ([]*cloudidentity.Membership, error) {
+	var out []*cloudidentity.Membership
+
+	call := c.CI.Groups.Memberships.List(groupName).Context(ctx)
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := retryDo(ctx, c, "Groups.Memberships.List", func() (*cloudidentity.ListMembershipsResponse, error) { return call.Do() })
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of %s: %v", groupName, err)
+		}
+		out = append(out, resp.Memberships...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return out, nil
+}
+
+// AddMember adds email to groupName with the given role (e.g. "MEMBER",
+// "MANAGER", "OWNER"). As with CreateGroup, this is modeled as a
+// long-running Operation with no Operations.Get exposed to poll it, so
+// AddMember rejects a not-yet-Done response with a distinct error instead of
+// misdecoding it.
+func (c *Client) AddMember(ctx context.Context, groupName, email, role string) (*cloudidentity.Membership, error) {
+	membership := &cloudidentity.Membership{
+		PreferredMemberKey: &cloudidentity.EntityKey{Id: email},
+		Roles:              []*cloudidentity.MembershipRole{{Name: role}},
+	}
+
+	createCall := c.CI.Groups.Memberships.Create(groupName, membership).Context(ctx)
+	op, err := retryDo(ctx, c, "Groups.Memberships.Create", func() (*cloudidentity.Operation, error) { return createCall.Do() })
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to %s: %v", email, groupName, err)
+	}
+	if !op.Done {
+		return nil, fmt.Errorf("add member %s to %s: operation %s not done yet; this client cannot poll Operations.Get", email, groupName, op.Name)
+	}
+
+	var created cloudidentity.Membership
+	if err := json.Unmarshal(op.Response, &created); err != nil {
+		return nil, fmt.Errorf("failed to decode membership for %s in %s: %v", email, groupName, err)
+	}
+	return &created, nil
+}
+
+// RemoveMember deletes a membership by its resource name (e.g.
+// "groups/123/memberships/456").
+func (c *Client) RemoveMember(ctx context.Context, membershipName string) error {
+	deleteCall := c.CI.Groups.Memberships.Delete(membershipName).Context(ctx)
+	if _, err := retryDo(ctx, c, "Groups.Memberships.Delete", func() (*cloudidentity.Operation, error) { return deleteCall.Do() }); err != nil {
+		return fmt.Errorf("failed to remove membership %s: %v", membershipName, err)
+	}
+	return nil
+}
+
+// ListUserGroups returns the groups a user directly belongs to, via the
+// Admin Directory `groups?userKey=` endpoint. It requires c.Directory to be
+// set.
+func (c *Client) ListUserGroups(ctx context.Context, email string) ([]*admin.Group, error) {
+	if c.Directory == nil {
+		return nil, fmt.Errorf("ListUserGroups requires a Directory client")
+	}
+
+	var out []*admin.Group
+	call := c.Directory.Groups.List().Context(ctx).UserKey(email)
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := retryDo(ctx, c, "Directory.Groups.List", func() (*admin.Groups, error) { return call.Do() })
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups for user %s: %v", email, err)
+		}
+		out = append(out, resp.Groups...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return out, nil
+}
+
+// celStringLiteral escapes s for safe interpolation inside a single-quoted
+// CEL string literal, so a memberKey containing a quote or backslash can't
+// break out of the query SearchTransitiveGroups expects.
+func celStringLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return s
+}
+
+// ListTransitiveMemberships resolves every group memberKey belongs to,
+// including indirect membership through nested groups, via
+// Groups.Memberships.SearchTransitiveGroups. The search runs across all
+// groups ("groups/-"); customerID is not used.
+func (c *Client) ListTransitiveMemberships(ctx context.Context, customerID, memberKey string) ([]*cloudidentity.GroupRelation, error) {
+	var out []*cloudidentity.GroupRelation
+
+	query := fmt.Sprintf("member_key_id == '%s'", celStringLiteral(memberKey))
+	call := c.CI.Groups.Memberships.SearchTransitiveGroups("groups/-").Context(ctx).Query(query)
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := retryDo(ctx, c, "Groups.Memberships.SearchTransitiveGroups", func() (*cloudidentity.SearchTransitiveGroupsResponse, error) { return call.Do() })
+		if err != nil {
+			return nil, fmt.Errorf("failed to search transitive groups for %s: %v", memberKey, err)
+		}
+		out = append(out, resp.Memberships...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return out, nil
+}
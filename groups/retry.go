@@ -0,0 +1,142 @@
+package groups
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/api/googleapi"
+)
+
+// requestSeq generates the request IDs used to correlate an API call's
+// attempts in the logs; it's shared across all Clients in the process.
+var requestSeq atomic.Uint64
+
+// ClientOption configures retry, rate limiting, and logging behavior for a
+// Client. The Admin SDK enforces aggressive per-user quotas, so every
+// Groups.*.Do() call made through a Client is retried with backoff and, when
+// WithRateLimit is set, throttled client-side instead of hammering the API
+// until it 429s.
+type ClientOption func(*Client)
+
+// WithRateLimit caps outbound requests to qps, with burst allowed above that
+// steady rate. A nil limiter (the default) applies no client-side limiting.
+func WithRateLimit(qps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+}
+
+// WithMaxRetries overrides the default retry budget for transient errors. An
+// n of zero keeps the default (defaultMaxRetries).
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithLogger attaches structured logging of request latency and outcome.
+// The logger never receives request bodies or credentials, so it's safe to
+// point at stdout/stderr even at debug level.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) { c.logger = logger }
+}
+
+const (
+	defaultMaxRetries  = 5
+	defaultRetryBase   = 500 * time.Millisecond
+	defaultRetryJitter = 250 * time.Millisecond
+)
+
+// retryDo runs fn, retrying with exponential backoff and jitter on 429s,
+// 5xxs, and quota errors, up to c.maxRetries times (defaultMaxRetries when
+// c.maxRetries is zero). It rate-limits via
+// c.limiter (if configured) before the first attempt, and logs every attempt
+// via c.logger (if configured) under a shared request ID so retries of the
+// same logical call can be correlated in the logs.
+func retryDo[T any](ctx context.Context, c *Client, op string, fn func() (T, error)) (T, error) {
+	var zero T
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return zero, err
+		}
+	}
+
+	requestID := strconv.FormatUint(requestSeq.Add(1), 10)
+
+	maxRetries := c.maxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	backoff := defaultRetryBase
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		start := time.Now()
+		result, err := fn()
+		latency := time.Since(start)
+		c.logAttempt(requestID, op, attempt, latency, err)
+
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if attempt == maxRetries || !isRetryable(err) {
+			break
+		}
+
+		sleep := backoff + time.Duration(rand.Int63n(int64(defaultRetryJitter)))
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	return zero, lastErr
+}
+
+// isRetryable reports whether err represents a transient condition worth
+// retrying: HTTP 429/5xx, or the Admin SDK's quotaExceeded /
+// userRateLimitExceeded error reasons.
+func isRetryable(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	if apiErr.Code == 429 || apiErr.Code >= 500 {
+		return true
+	}
+
+	for _, e := range apiErr.Errors {
+		if e.Reason == "quotaExceeded" || e.Reason == "userRateLimitExceeded" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Client) logAttempt(requestID, op string, attempt int, latency time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []any{
+		slog.String("request_id", requestID),
+		slog.String("op", op),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		c.logger.Warn("directory api request failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	c.logger.Debug("directory api request", attrs...)
+}
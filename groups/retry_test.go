@@ -0,0 +1,47 @@
+package groups
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"non-googleapi error", errors.New("boom"), false},
+		{"429", &googleapi.Error{Code: 429}, true},
+		{"500", &googleapi.Error{Code: 500}, true},
+		{"503", &googleapi.Error{Code: 503}, true},
+		{"404", &googleapi.Error{Code: 404}, false},
+		{"400 bad request", &googleapi.Error{Code: 400}, false},
+		{
+			"quotaExceeded reason",
+			&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}},
+			true,
+		},
+		{
+			"userRateLimitExceeded reason",
+			&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}},
+			true,
+		},
+		{
+			"unrelated 403 reason",
+			&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}